@@ -0,0 +1,89 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Fit resizes src to fit entirely inside width x height, preserving aspect
+// ratio, and pads any leftover space with bg (letterboxing). Unlike ToSize,
+// Fit never crops; the whole source image is always visible.
+func Fit(src image.Image, width, height int, bg color.Color, kernel Kernel) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	scale := float64(width) / float64(srcW)
+	if hScale := float64(height) / float64(srcH); hScale < scale {
+		scale = hScale
+	}
+
+	newW := int(float64(srcW)*scale + 0.5)
+	newH := int(float64(srcH)*scale + 0.5)
+	if newW < 1 {
+		newW = 1
+	}
+	if newH < 1 {
+		newH = 1
+	}
+
+	resized := resample(src, newW, newH, kernel)
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+
+	offset := image.Pt((width-newW)/2, (height-newH)/2)
+	dstRect := image.Rectangle{Min: offset, Max: offset.Add(image.Pt(newW, newH))}
+	draw.Draw(canvas, dstRect, resized, image.Point{}, draw.Src)
+
+	return canvas
+}
+
+// Fill resizes src so it completely covers width x height, preserving
+// aspect ratio, then crops the overflow using anchor. Unlike ToSize, Fill
+// resamples the source rather than only ever cropping it, so the result is
+// always exactly width x height regardless of the source's dimensions.
+func Fill(src image.Image, width, height int, anchor Anchor, kernel Kernel) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	scale := float64(width) / float64(srcW)
+	if hScale := float64(height) / float64(srcH); hScale > scale {
+		scale = hScale
+	}
+
+	newW := int(float64(srcW)*scale + 0.5)
+	newH := int(float64(srcH)*scale + 0.5)
+	if newW < width {
+		newW = width
+	}
+	if newH < height {
+		newH = height
+	}
+
+	resized := resample(src, newW, newH, kernel)
+	return ToSize(resized, width, height, anchor)
+}
+
+// AspectCrop computes the largest sub-rectangle of src with the given
+// aspect ratio (wRatio:hRatio) and crops it using anchor, without
+// resampling. Use Fill instead if the output must also match an exact
+// pixel size.
+func AspectCrop(src image.Image, wRatio, hRatio float64, anchor Anchor) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	targetRatio := wRatio / hRatio
+	srcRatio := float64(srcW) / float64(srcH)
+
+	var width, height int
+	if srcRatio > targetRatio {
+		height = srcH
+		width = int(float64(height)*targetRatio + 0.5)
+	} else {
+		width = srcW
+		height = int(float64(width)/targetRatio + 0.5)
+	}
+
+	return ToSize(src, width, height, anchor)
+}
@@ -0,0 +1,71 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFitPadsAndPreservesAspect(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 50)) // 2:1 aspect
+	fillUniform(src, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+	out := Fit(src, 60, 60, color.RGBA{A: 255}, NearestNeighbor)
+
+	b := out.Bounds()
+	if b.Dx() != 60 || b.Dy() != 60 {
+		t.Fatalf("Fit() bounds = %v, want 60x60", b)
+	}
+
+	// Scaled image is 60x30, letterboxed with 15px of black padding above
+	// and below.
+	if r, _, _, _ := out.At(0, 0).RGBA(); r != 0 {
+		t.Errorf("Fit() pad pixel at (0,0) = %#x, want black background", r)
+	}
+	if r, _, _, _ := out.At(30, 30).RGBA(); r>>8 != 255 {
+		t.Errorf("Fit() center pixel = %#x, want white (from the resized source)", r)
+	}
+}
+
+func TestFillAlwaysFillsExactSize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 37, 81)) // an aspect ratio that doesn't divide evenly
+	fillUniform(src, color.RGBA{G: 255, A: 255})
+
+	out := Fill(src, 50, 80, Center, NearestNeighbor)
+
+	b := out.Bounds()
+	if b.Dx() != 50 || b.Dy() != 80 {
+		t.Errorf("Fill() bounds = %v, want exactly 50x80", b)
+	}
+}
+
+func TestAspectCropDoesNotResample(t *testing.T) {
+	// Left half red, right half blue; a pure crop must preserve these
+	// colors exactly, since AspectCrop never resamples.
+	src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 40; x++ {
+			if x < 20 {
+				src.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				src.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+
+	out := AspectCrop(src, 1, 1, Center)
+
+	b := out.Bounds()
+	if b.Dx() != 20 || b.Dy() != 20 {
+		t.Fatalf("AspectCrop() bounds = %v, want 20x20 for a 1:1 ratio on a 40x20 source", b)
+	}
+
+	// Center anchor on a 40x20 source crops x in [10, 30): columns 10-19
+	// come from the red half, 20-29 from the blue half.
+	if r, _, _, _ := out.At(0, 0).RGBA(); r>>8 != 255 {
+		t.Errorf("AspectCrop() pixel (0,0) = %#x, want the source's exact red", r)
+	}
+	if _, _, b, _ := out.At(19, 0).RGBA(); b>>8 != 255 {
+		t.Errorf("AspectCrop() pixel (19,0) = %#x, want the source's exact blue", b)
+	}
+}
@@ -0,0 +1,114 @@
+package crop
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+)
+
+// NormalizedRect describes a rectangle in aspect-space, where each field is
+// a fraction of the canvas dimension it applies to (0 is the canvas edge,
+// 1 is the opposite edge). It lets a collage layout be designed once and
+// rendered at any output resolution.
+type NormalizedRect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// FrameTranslate maps a NormalizedRect to pixel coordinates for a canvas of
+// the given output dimensions.
+func FrameTranslate(frame NormalizedRect, outWidth, outHeight int) image.Rectangle {
+	return image.Rect(
+		int(frame.X0*float64(outWidth)),
+		int(frame.Y0*float64(outHeight)),
+		int(frame.X1*float64(outWidth)),
+		int(frame.Y1*float64(outHeight)),
+	)
+}
+
+// Photo describes one image placed on a Collage canvas: where to read it
+// from, which part of it to use, and where that part lands on the canvas.
+type Photo struct {
+	// Path is the source image file path, used when Image is nil.
+	Path string
+	// Image is the source image. When set, it takes precedence over Path.
+	Image image.Image
+	// SrcRect is the crop rectangle in the source image's coordinate space.
+	SrcRect image.Rectangle
+	// Frame is the destination rectangle in canvas aspect-space.
+	Frame NormalizedRect
+}
+
+// CollageSpec describes a canvas and the photos placed on it.
+type CollageSpec struct {
+	// Width and Height are the output canvas dimensions in pixels.
+	Width, Height int
+	// Background fills the canvas before photos are composited. It is
+	// ignored when BackgroundImage is set. Defaults to white.
+	Background color.Color
+	// BackgroundImage, if set, is drawn instead of Background, unscaled,
+	// with its point (0,0) at the canvas's top-left corner. It is not
+	// resized, so a smaller image leaves the rest of the canvas transparent.
+	BackgroundImage image.Image
+	// Photos are composited onto the canvas in order.
+	Photos []Photo
+}
+
+// Collage renders spec into a single image: each photo is cropped with
+// Rectangle, resampled to its destination frame size, and composited onto
+// the background. Callers encode the result with SaveJPEG or SavePNG.
+func Collage(spec CollageSpec) (image.Image, error) {
+	if spec.Width <= 0 || spec.Height <= 0 {
+		return nil, fmt.Errorf("crop: collage width and height must be positive, got %dx%d", spec.Width, spec.Height)
+	}
+
+	canvas := image.NewRGBA(image.Rect(0, 0, spec.Width, spec.Height))
+	if spec.BackgroundImage != nil {
+		draw.Draw(canvas, canvas.Bounds(), spec.BackgroundImage, image.Point{}, draw.Src)
+	} else {
+		bg := spec.Background
+		if bg == nil {
+			bg = color.White
+		}
+		draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	}
+
+	for i, photo := range spec.Photos {
+		src, err := photo.resolve()
+		if err != nil {
+			return nil, fmt.Errorf("crop: collage photo %d: %w", i, err)
+		}
+
+		cropped := Rectangle(src, photo.SrcRect)
+		destRect := FrameTranslate(photo.Frame, spec.Width, spec.Height)
+		if destRect.Dx() <= 0 || destRect.Dy() <= 0 {
+			continue
+		}
+
+		resized := resample(cropped, destRect.Dx(), destRect.Dy(), ApproxBiLinear)
+		draw.Draw(canvas, destRect, resized, image.Point{}, draw.Src)
+	}
+
+	return canvas, nil
+}
+
+// resolve returns the photo's source image, decoding Path if Image is unset.
+func (p Photo) resolve() (image.Image, error) {
+	if p.Image != nil {
+		return p.Image, nil
+	}
+	if p.Path == "" {
+		return nil, errors.New("photo has neither Image nor Path set")
+	}
+
+	f, err := os.Open(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
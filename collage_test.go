@@ -0,0 +1,98 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFrameTranslate(t *testing.T) {
+	got := FrameTranslate(NormalizedRect{X0: 0.25, Y0: 0.5, X1: 0.75, Y1: 1}, 200, 100)
+	want := image.Rect(50, 50, 150, 100)
+	if got != want {
+		t.Errorf("FrameTranslate() = %v, want %v", got, want)
+	}
+}
+
+func TestCollagePixelPlacement(t *testing.T) {
+	red := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	fillUniform(red, color.RGBA{R: 255, A: 255})
+
+	spec := CollageSpec{
+		Width:      100,
+		Height:     100,
+		Background: color.RGBA{B: 255, A: 255},
+		Photos: []Photo{
+			{
+				Image:   red,
+				SrcRect: red.Bounds(),
+				Frame:   NormalizedRect{X0: 0, Y0: 0, X1: 0.5, Y1: 0.5},
+			},
+		},
+	}
+
+	out, err := Collage(spec)
+	if err != nil {
+		t.Fatalf("Collage() error = %v", err)
+	}
+
+	// Inside the photo's frame: red.
+	if r, g, b, _ := out.At(10, 10).RGBA(); r>>8 != 255 || g>>8 != 0 || b>>8 != 0 {
+		t.Errorf("pixel inside frame = (%d,%d,%d), want red", r>>8, g>>8, b>>8)
+	}
+	// Outside the photo's frame: the background color.
+	if r, g, b, _ := out.At(90, 90).RGBA(); r>>8 != 0 || g>>8 != 0 || b>>8 != 255 {
+		t.Errorf("pixel outside frame = (%d,%d,%d), want background blue", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestCollageRejectsNonPositiveDimensions(t *testing.T) {
+	if _, err := Collage(CollageSpec{Width: 0, Height: 10}); err == nil {
+		t.Error("Collage() with zero width should return an error")
+	}
+}
+
+func TestPhotoResolveImageTakesPrecedenceOverPath(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	p := Photo{Image: img, Path: "/does/not/exist.png"}
+
+	got, err := p.resolve()
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if got != image.Image(img) {
+		t.Error("resolve() should return the Image field when both Image and Path are set")
+	}
+}
+
+func TestPhotoResolveFromPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "photo.png")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	p := Photo{Path: path}
+	got, err := p.resolve()
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+	if b := got.Bounds(); b.Dx() != 4 || b.Dy() != 4 {
+		t.Errorf("resolve() decoded bounds = %v, want 4x4", b)
+	}
+}
+
+func TestPhotoResolveRequiresImageOrPath(t *testing.T) {
+	if _, err := (Photo{}).resolve(); err == nil {
+		t.Error("resolve() with neither Image nor Path set should return an error")
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"image/jpeg"
 	"image/png"
 	"io"
-	"os"
 )
 
 // Anchor specifies the reference point for cropping operations.
@@ -24,6 +23,9 @@ const (
 	BottomLeft
 	// BottomRight crops from the bottom-right corner.
 	BottomRight
+	// Smart crops using a content-aware saliency map instead of a fixed
+	// reference point. See SmartCrop for the underlying algorithm.
+	Smart
 )
 
 // Rectangle crops an image to the specified rectangle.
@@ -78,6 +80,8 @@ func ToSize(src image.Image, width, height int, anchor Anchor) image.Image {
 	case BottomRight:
 		x = srcW - width
 		y = srcH - height
+	case Smart:
+		x, y = smartCropOrigin(src, width, height, DefaultSmartCropOptions())
 	}
 
 	rect := image.Rect(x, y, x+width, y+height)
@@ -112,15 +116,11 @@ func Margins(src image.Image, top, right, bottom, left int) image.Image {
 	return Rectangle(src, rect)
 }
 
-// CropFromFile reads an image file and crops it.
+// CropFromFile reads an image file and crops it. JPEGs carrying an EXIF
+// Orientation tag are auto-rotated/flipped upright before the crop
+// rectangle is applied; see DecodeFile.
 func CropFromFile(path string, rect image.Rectangle) (image.Image, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	src, _, err := image.Decode(f)
+	src, err := DecodeFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +128,11 @@ func CropFromFile(path string, rect image.Rectangle) (image.Image, error) {
 	return Rectangle(src, rect), nil
 }
 
-// SaveJPEG saves the cropped image as JPEG.
+// SaveJPEG saves the cropped image as JPEG. JPEG has no alpha channel, so
+// the encoder simply drops it: transparent pixels (e.g. from CircleCrop or
+// RoundedRect) come out as whatever their zero-alpha RGB happened to be,
+// typically black. Use SaveJPEGFlat instead to flatten against a chosen
+// background color.
 func SaveJPEG(img image.Image, w io.Writer, quality int) error {
 	if quality <= 0 || quality > 100 {
 		quality = 85
@@ -0,0 +1,333 @@
+package crop
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+)
+
+// exifHeader is the APP1 payload prefix that marks it as EXIF rather than
+// some other APP1 use (e.g. XMP).
+const exifHeader = "Exif\x00\x00"
+
+// orientationTag is the EXIF tag ID for image orientation.
+const orientationTag = 0x0112
+
+// orientationNormal is the EXIF orientation value for an upright, unflipped
+// image; it requires no transform.
+const orientationNormal = 1
+
+// DecodeFile opens an image file and decodes it, auto-rotating/flipping
+// JPEGs according to their EXIF Orientation tag (0x0112) so the returned
+// image.Image is always upright.
+func DecodeFile(path string) (image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOriented(data)
+}
+
+// DecodeReader is DecodeFile for an already-open reader.
+func DecodeReader(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOriented(data)
+}
+
+func decodeOriented(data []byte) (image.Image, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format != "jpeg" {
+		return img, nil
+	}
+
+	orientation := readOrientation(data)
+	if orientation == orientationNormal {
+		return img, nil
+	}
+	return applyOrientation(img, orientation), nil
+}
+
+// ExtractEXIF returns the raw EXIF payload (the APP1 segment contents,
+// including the "Exif\x00\x00" header) from JPEG-encoded data, or nil if
+// the data carries no EXIF segment. Pass the result to
+// SaveJPEGWithOptions' JPEGSaveOptions.EXIF to round-trip metadata.
+func ExtractEXIF(data []byte) []byte {
+	return findAPP1(data)
+}
+
+// readOrientation returns the EXIF Orientation tag from JPEG-encoded data,
+// or orientationNormal if the data has no EXIF segment or no orientation
+// tag.
+func readOrientation(data []byte) int {
+	exif := findAPP1(data)
+	if exif == nil {
+		return orientationNormal
+	}
+
+	value, ok := findOrientationEntry(exif[len(exifHeader):])
+	if !ok {
+		return orientationNormal
+	}
+	return value
+}
+
+// findAPP1 scans JPEG markers for the first APP1 segment carrying an EXIF
+// header, returning its full payload (header included).
+func findAPP1(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			break
+		}
+		marker := data[i+1]
+		if marker == 0xD8 || marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			i += 2
+			continue
+		}
+		if marker == 0xDA { // start of scan: no more metadata markers follow
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			break
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte(exifHeader)) {
+			return segment
+		}
+		i += 2 + length
+	}
+	return nil
+}
+
+// tiffByteOrder returns the byte order a TIFF/EXIF blob declares in its
+// first two bytes.
+func tiffByteOrder(tiff []byte) (binary.ByteOrder, error) {
+	if len(tiff) < 8 {
+		return nil, errors.New("crop: EXIF data too short")
+	}
+	switch string(tiff[0:2]) {
+	case "II":
+		return binary.LittleEndian, nil
+	case "MM":
+		return binary.BigEndian, nil
+	default:
+		return nil, errors.New("crop: invalid TIFF byte order")
+	}
+}
+
+// findOrientationEntry walks IFD0 of the TIFF blob tiff (the EXIF payload
+// with its "Exif\x00\x00" header already stripped) looking for the
+// Orientation tag, returning its value.
+func findOrientationEntry(tiff []byte) (int, bool) {
+	order, err := tiffByteOrder(tiff)
+	if err != nil || order.Uint16(tiff[2:4]) != 0x002A {
+		return 0, false
+	}
+
+	ifdOffset := order.Uint32(tiff[4:8])
+	if int(ifdOffset)+2 > len(tiff) {
+		return 0, false
+	}
+
+	entryCount := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+	base := int(ifdOffset) + 2
+	for i := 0; i < entryCount; i++ {
+		off := base + i*12
+		if off+12 > len(tiff) {
+			break
+		}
+		entry := tiff[off : off+12]
+		if order.Uint16(entry[0:2]) == orientationTag {
+			return int(order.Uint16(entry[8:10])), true
+		}
+	}
+	return 0, false
+}
+
+// applyOrientation rotates/flips img according to the EXIF orientation
+// values defined by the TIFF 6.0 / Exif spec.
+func applyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return flipH(img)
+	case 3:
+		return rotate180(img)
+	case 4:
+		return flipV(img)
+	case 5:
+		return rotate270(flipH(img))
+	case 6:
+		return rotate90(img)
+	case 7:
+		return rotate90(flipH(img))
+	case 8:
+		return rotate270(img)
+	default:
+		return img
+	}
+}
+
+func flipH(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(b.Dx()-1-x, y, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+func flipV(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(x, b.Dy()-1-y, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// rotate90 rotates img 90 degrees clockwise.
+func rotate90(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(b.Dy()-1-y, x, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// rotate180 rotates img 180 degrees.
+func rotate180(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(b.Dx()-1-x, b.Dy()-1-y, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// rotate270 rotates img 270 degrees clockwise (90 degrees counterclockwise).
+func rotate270(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dy(), b.Dx()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dst.Set(y, b.Dx()-1-x, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// JPEGSaveOptions configures SaveJPEGWithOptions.
+type JPEGSaveOptions struct {
+	// Quality is the JPEG encoding quality (1-100). Values outside that
+	// range fall back to the same default as SaveJPEG.
+	Quality int
+	// KeepEXIF, when true, writes EXIF into the encoded JPEG's APP1
+	// segment. The Orientation tag is reset to normal, since DecodeFile
+	// and DecodeReader already bake orientation into the pixels before
+	// cropping.
+	KeepEXIF bool
+	// EXIF is the raw APP1 payload to preserve, as returned by
+	// ExtractEXIF. Required when KeepEXIF is true; ignored otherwise.
+	EXIF []byte
+}
+
+// SaveJPEGWithOptions saves img as JPEG, optionally preserving EXIF
+// metadata extracted from the original file via ExtractEXIF. Without
+// KeepEXIF it behaves exactly like SaveJPEG.
+func SaveJPEGWithOptions(img image.Image, w io.Writer, opts JPEGSaveOptions) error {
+	if !opts.KeepEXIF || len(opts.EXIF) == 0 {
+		return SaveJPEG(img, w, opts.Quality)
+	}
+
+	var buf bytes.Buffer
+	if err := SaveJPEG(img, &buf, opts.Quality); err != nil {
+		return err
+	}
+	encoded := buf.Bytes()
+	if len(encoded) < 2 || encoded[0] != 0xFF || encoded[1] != 0xD8 {
+		return fmt.Errorf("crop: unexpected JPEG encoder output")
+	}
+
+	exif := normalizeOrientation(opts.EXIF)
+
+	if _, err := w.Write(encoded[:2]); err != nil {
+		return err
+	}
+	if err := writeAPP1(w, exif); err != nil {
+		return err
+	}
+	_, err := w.Write(encoded[2:])
+	return err
+}
+
+// writeAPP1 writes exif (including its "Exif\x00\x00" header) as a JPEG
+// APP1 marker segment.
+func writeAPP1(w io.Writer, exif []byte) error {
+	length := len(exif) + 2
+	header := [4]byte{0xFF, 0xE1, byte(length >> 8), byte(length)}
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(exif)
+	return err
+}
+
+// normalizeOrientation returns a copy of exif with its Orientation tag (if
+// any) set to orientationNormal, since the pixels it will be attached to
+// have already been rotated/flipped to match.
+func normalizeOrientation(exif []byte) []byte {
+	out := make([]byte, len(exif))
+	copy(out, exif)
+
+	tiff := out[len(exifHeader):]
+	order, err := tiffByteOrder(tiff)
+	if err != nil || order.Uint16(tiff[2:4]) != 0x002A {
+		return out
+	}
+
+	ifdOffset := order.Uint32(tiff[4:8])
+	if int(ifdOffset)+2 > len(tiff) {
+		return out
+	}
+
+	entryCount := int(order.Uint16(tiff[ifdOffset : ifdOffset+2]))
+	base := int(ifdOffset) + 2
+	for i := 0; i < entryCount; i++ {
+		off := base + i*12
+		if off+12 > len(tiff) {
+			break
+		}
+		entry := tiff[off : off+12]
+		if order.Uint16(entry[0:2]) == orientationTag {
+			order.PutUint16(entry[8:10], orientationNormal)
+			break
+		}
+	}
+	return out
+}
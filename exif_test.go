@@ -0,0 +1,156 @@
+package crop
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// buildEXIF returns a minimal little-endian EXIF payload (including the
+// "Exif\x00\x00" header) whose IFD0 contains a single Orientation entry.
+func buildEXIF(orientation uint16) []byte {
+	var tiff []byte
+	tiff = append(tiff, 'I', 'I')               // byte order
+	tiff = append(tiff, 0x2A, 0x00)             // TIFF magic number
+	tiff = append(tiff, 0x08, 0x00, 0x00, 0x00) // IFD0 offset
+
+	tiff = append(tiff, 0x01, 0x00)             // 1 entry
+	tiff = append(tiff, 0x12, 0x01)             // tag 0x0112 (Orientation)
+	tiff = append(tiff, 0x03, 0x00)             // type SHORT
+	tiff = append(tiff, 0x01, 0x00, 0x00, 0x00) // count 1
+	tiff = append(tiff, byte(orientation), byte(orientation>>8), 0x00, 0x00)
+	tiff = append(tiff, 0x00, 0x00, 0x00, 0x00) // next IFD offset
+
+	return append([]byte(exifHeader), tiff...)
+}
+
+// wrapJPEG builds a minimal JPEG byte stream (SOI + APP1) carrying exif.
+func wrapJPEG(exif []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0xFF, 0xD8}) // SOI
+
+	length := len(exif) + 2
+	buf.Write([]byte{0xFF, 0xE1, byte(length >> 8), byte(length)})
+	buf.Write(exif)
+	return buf.Bytes()
+}
+
+func TestReadOrientation(t *testing.T) {
+	for orientation := 1; orientation <= 8; orientation++ {
+		jpegData := wrapJPEG(buildEXIF(uint16(orientation)))
+		got := readOrientation(jpegData)
+		if got != orientation {
+			t.Errorf("orientation %d: readOrientation() = %d, want %d", orientation, got, orientation)
+		}
+	}
+}
+
+func TestReadOrientationNoEXIF(t *testing.T) {
+	got := readOrientation([]byte{0xFF, 0xD8, 0xFF, 0xD9})
+	if got != orientationNormal {
+		t.Errorf("readOrientation() with no EXIF = %d, want %d", got, orientationNormal)
+	}
+}
+
+func TestExtractEXIF(t *testing.T) {
+	exif := buildEXIF(6)
+	jpegData := wrapJPEG(exif)
+
+	got := ExtractEXIF(jpegData)
+	if !bytes.Equal(got, exif) {
+		t.Errorf("ExtractEXIF() = %v, want %v", got, exif)
+	}
+
+	if ExtractEXIF([]byte{0xFF, 0xD8, 0xFF, 0xD9}) != nil {
+		t.Error("ExtractEXIF() on data with no EXIF should return nil")
+	}
+}
+
+func TestNormalizeOrientation(t *testing.T) {
+	exif := buildEXIF(6)
+	original := append([]byte(nil), exif...)
+
+	normalized := normalizeOrientation(exif)
+
+	value, ok := findOrientationEntry(normalized[len(exifHeader):])
+	if !ok || value != orientationNormal {
+		t.Errorf("normalizeOrientation() orientation = %d, ok=%v, want %d", value, ok, orientationNormal)
+	}
+	if !bytes.Equal(exif, original) {
+		t.Error("normalizeOrientation() mutated its input")
+	}
+}
+
+func TestApplyOrientationIdentity(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	out := applyOrientation(img, orientationNormal)
+	if out != image.Image(img) {
+		t.Error("applyOrientation() with normal orientation should return the input unchanged")
+	}
+}
+
+func TestApplyOrientationTransforms(t *testing.T) {
+	// 2x1 image: red at (0,0), blue at (1,0).
+	src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.RGBA{R: 255, A: 255})
+	src.Set(1, 0, color.RGBA{B: 255, A: 255})
+
+	isRed := func(c color.Color) bool {
+		r, _, _, _ := c.RGBA()
+		return r>>8 == 255
+	}
+	isBlue := func(c color.Color) bool {
+		_, _, b, _ := c.RGBA()
+		return b>>8 == 255
+	}
+
+	tests := []struct {
+		name        string
+		orientation int
+		wantW       int
+		wantH       int
+		checkAt     image.Point
+		want        func(color.Color) bool
+	}{
+		{"flipH", 2, 2, 1, image.Pt(0, 0), isBlue},
+		{"rotate180", 3, 2, 1, image.Pt(0, 0), isBlue},
+		{"rotate90", 6, 1, 2, image.Pt(0, 0), isRed},
+		{"rotate270", 8, 1, 2, image.Pt(0, 0), isBlue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := applyOrientation(src, tt.orientation)
+			b := out.Bounds()
+			if b.Dx() != tt.wantW || b.Dy() != tt.wantH {
+				t.Fatalf("bounds = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.wantW, tt.wantH)
+			}
+			if got := out.At(tt.checkAt.X, tt.checkAt.Y); !tt.want(got) {
+				t.Errorf("pixel at %v = %v, unexpected color", tt.checkAt, got)
+			}
+		})
+	}
+}
+
+func TestFindAPP1StopsAtStartOfScan(t *testing.T) {
+	data := []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02}
+	if findAPP1(data) != nil {
+		t.Error("findAPP1() should not find EXIF past the start-of-scan marker")
+	}
+}
+
+func TestTiffByteOrder(t *testing.T) {
+	if _, err := tiffByteOrder([]byte("short")); err == nil {
+		t.Error("tiffByteOrder() on truncated data should return an error")
+	}
+
+	order, err := tiffByteOrder([]byte{'M', 'M', 0x00, 0x2A, 0, 0, 0, 8})
+	if err != nil {
+		t.Fatalf("tiffByteOrder() error = %v", err)
+	}
+	if order != binary.BigEndian {
+		t.Error("tiffByteOrder() should detect big-endian \"MM\" byte order")
+	}
+}
@@ -0,0 +1,139 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"io"
+)
+
+// maskSubgridSize is the side length of the subgrid used to supersample
+// mask edges for anti-aliasing.
+const maskSubgridSize = 4
+
+// CircleCrop crops src to a square (see Square) using anchor, then masks it
+// to the circle inscribed in that square. Pixels outside the circle are
+// transparent; pixels near the edge are anti-aliased via 4x4 subgrid
+// coverage sampling. The result is suitable for avatar-style pipelines;
+// save it with SavePNG to preserve the alpha channel.
+func CircleCrop(src image.Image, anchor Anchor) *image.RGBA {
+	square := Square(src, anchor)
+	bounds := square.Bounds()
+	mask := &circleMask{size: bounds.Dx()}
+
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.DrawMask(dst, dst.Bounds(), square, bounds.Min, mask, image.Point{}, draw.Over)
+	return dst
+}
+
+// RoundedRect crops src to rect and masks its corners to the given radius
+// (in pixels), leaving the interior opaque and the corners transparent.
+// Edges are anti-aliased via 4x4 subgrid coverage sampling. Save the result
+// with SavePNG to preserve the alpha channel.
+func RoundedRect(src image.Image, rect image.Rectangle, radius int) *image.RGBA {
+	cropped := Rectangle(src, rect)
+	bounds := cropped.Bounds()
+	mask := &roundedRectMask{width: bounds.Dx(), height: bounds.Dy(), radius: radius}
+
+	dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.DrawMask(dst, dst.Bounds(), cropped, bounds.Min, mask, image.Point{}, draw.Over)
+	return dst
+}
+
+// SaveJPEGFlat flattens img onto a solid background color before encoding
+// it as JPEG. Use this for images carrying transparency (e.g. from
+// CircleCrop or RoundedRect), since JPEG has no alpha channel.
+func SaveJPEGFlat(img image.Image, w io.Writer, quality int, bg color.Color) error {
+	bounds := img.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+	return SaveJPEG(flat, w, quality)
+}
+
+// circleMask is an image.Image whose At returns full coverage inside its
+// inscribed circle, none outside, and supersampled coverage at the edge.
+type circleMask struct {
+	size int
+}
+
+func (m *circleMask) ColorModel() color.Model { return color.AlphaModel }
+func (m *circleMask) Bounds() image.Rectangle { return image.Rect(0, 0, m.size, m.size) }
+
+func (m *circleMask) At(x, y int) color.Color {
+	r := float64(m.size) / 2
+	return color.Alpha{A: subgridCoverage(x, y, func(px, py float64) bool {
+		dx := px - r
+		dy := py - r
+		return dx*dx+dy*dy <= r*r
+	})}
+}
+
+// roundedRectMask is an image.Image whose At returns full coverage inside a
+// rounded rectangle of the given size and corner radius, none outside, and
+// supersampled coverage at the edge.
+type roundedRectMask struct {
+	width, height int
+	radius        int
+}
+
+func (m *roundedRectMask) ColorModel() color.Model { return color.AlphaModel }
+func (m *roundedRectMask) Bounds() image.Rectangle {
+	return image.Rect(0, 0, m.width, m.height)
+}
+
+func (m *roundedRectMask) At(x, y int) color.Color {
+	w, h, r := float64(m.width), float64(m.height), float64(m.radius)
+	return color.Alpha{A: subgridCoverage(x, y, func(px, py float64) bool {
+		return insideRoundedRect(px, py, w, h, r)
+	})}
+}
+
+// insideRoundedRect reports whether point (px, py) lies inside a w x h
+// rectangle whose corners are rounded to radius r.
+func insideRoundedRect(px, py, w, h, r float64) bool {
+	if px < 0 || py < 0 || px >= w || py >= h {
+		return false
+	}
+
+	// Only the four corner regions need a distance check; the rest of the
+	// rectangle is always inside.
+	cx, cy := px, py
+	switch {
+	case px < r && py < r:
+		cx, cy = r, r
+	case px >= w-r && py < r:
+		cx, cy = w-r, r
+	case px < r && py >= h-r:
+		cx, cy = r, h-r
+	case px >= w-r && py >= h-r:
+		cx, cy = w-r, h-r
+	default:
+		return true
+	}
+
+	dx := px - cx
+	dy := py - cy
+	return dx*dx+dy*dy <= r*r
+}
+
+// subgridCoverage supersamples inside on a maskSubgridSize x
+// maskSubgridSize subgrid within pixel (x, y), returning an 8-bit coverage
+// value for anti-aliased mask edges.
+func subgridCoverage(x, y int, inside func(px, py float64) bool) uint8 {
+	const n = maskSubgridSize
+	step := 1.0 / n
+	offset := step / 2
+
+	var hit int
+	for sy := 0; sy < n; sy++ {
+		py := float64(y) + offset + float64(sy)*step
+		for sx := 0; sx < n; sx++ {
+			px := float64(x) + offset + float64(sx)*step
+			if inside(px, py) {
+				hit++
+			}
+		}
+	}
+	return uint8(hit * 255 / (n * n))
+}
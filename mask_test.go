@@ -0,0 +1,73 @@
+package crop
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+func alphaOf(c color.Color) uint32 {
+	_, _, _, a := c.RGBA()
+	return a
+}
+
+func TestCircleCropAlphaCornerVsCenter(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	fillUniform(src, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+	out := CircleCrop(src, Center)
+
+	if a := alphaOf(out.At(10, 10)); a != 0xffff {
+		t.Errorf("CircleCrop() center alpha = %#x, want fully opaque", a)
+	}
+	if a := alphaOf(out.At(0, 0)); a != 0 {
+		t.Errorf("CircleCrop() corner alpha = %#x, want fully transparent", a)
+	}
+}
+
+func TestRoundedRectAlphaCornerVsCenter(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	fillUniform(src, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+	out := RoundedRect(src, src.Bounds(), 5)
+
+	if a := alphaOf(out.At(10, 10)); a != 0xffff {
+		t.Errorf("RoundedRect() center alpha = %#x, want fully opaque", a)
+	}
+	if a := alphaOf(out.At(0, 0)); a != 0 {
+		t.Errorf("RoundedRect() corner alpha = %#x, want fully transparent", a)
+	}
+}
+
+func TestSubgridCoverageExtremes(t *testing.T) {
+	if got := subgridCoverage(0, 0, func(float64, float64) bool { return true }); got != 255 {
+		t.Errorf("subgridCoverage() always-inside = %d, want 255", got)
+	}
+	if got := subgridCoverage(0, 0, func(float64, float64) bool { return false }); got != 0 {
+		t.Errorf("subgridCoverage() always-outside = %d, want 0", got)
+	}
+}
+
+func TestSaveJPEGFlatFlattensAgainstBackground(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	// Leave src fully transparent; the result should take on bg entirely.
+
+	var buf bytes.Buffer
+	bg := color.RGBA{G: 255, A: 255}
+	if err := SaveJPEGFlat(src, &buf, 90, bg); err != nil {
+		t.Fatalf("SaveJPEGFlat() error = %v", err)
+	}
+
+	decoded, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatalf("jpeg.Decode() error = %v", err)
+	}
+
+	r, g, b, _ := decoded.At(4, 4).RGBA()
+	// JPEG is lossy, so allow some tolerance around pure green.
+	if r>>8 > 40 || g>>8 < 200 || b>>8 > 40 {
+		t.Errorf("SaveJPEGFlat() pixel = (%d,%d,%d), want approximately green background", r>>8, g>>8, b>>8)
+	}
+}
@@ -0,0 +1,264 @@
+package crop
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// CropFunc transforms a decoded image, e.g. crop.Rectangle or crop.Square
+// bound to specific arguments via a closure.
+type CropFunc func(image.Image) image.Image
+
+// OutputFormat selects how a Pipeline encodes its results.
+type OutputFormat int
+
+const (
+	// AutoFormat picks PNG or JPEG based on the extension of the Source's
+	// Name, defaulting to JPEG when the extension is not ".png". RunDir
+	// writes each output under the source file's own name and extension.
+	AutoFormat OutputFormat = iota
+	// ForcePNG always encodes as PNG, regardless of file extension.
+	ForcePNG
+	// ForceJPEG always encodes as JPEG, regardless of file extension.
+	ForceJPEG
+)
+
+// Source pairs a name (used for format detection, destination mapping, and
+// progress/error reporting) with the image bytes to decode.
+type Source struct {
+	Name   string
+	Reader io.Reader
+}
+
+// PipelineResult reports the outcome of processing one Source.
+type PipelineResult struct {
+	Name string
+	Err  error
+}
+
+// PipelineOptions configures a Pipeline.
+type PipelineOptions struct {
+	// Workers is the size of the bounded worker pool. Defaults to
+	// runtime.NumCPU() when <= 0.
+	Workers int
+	// ContinueOnError, when false, stops submitting new work after the
+	// first error (fail-fast). Work already handed to a worker still
+	// runs to completion.
+	ContinueOnError bool
+	// Format selects the output encoding. Defaults to AutoFormat.
+	Format OutputFormat
+	// JPEGQuality is passed to SaveJPEG; 0 uses SaveJPEG's own default.
+	JPEGQuality int
+	// Progress, if set, is called after each file completes (successfully
+	// or not) with the count of files completed so far and the total.
+	// total is 0 when the total is unknown ahead of time (plain Run).
+	Progress func(name string, done, total int)
+}
+
+// Pipeline applies a CropFunc across many images using a bounded worker
+// pool, so callers processing directory- or gallery-scale batches don't
+// need to re-implement goroutine plumbing themselves.
+type Pipeline struct {
+	crop CropFunc
+	opts PipelineOptions
+}
+
+// NewPipeline returns a Pipeline that applies cropFn to every image it
+// processes.
+func NewPipeline(cropFn CropFunc, opts PipelineOptions) *Pipeline {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	return &Pipeline{crop: cropFn, opts: opts}
+}
+
+// RunDir walks srcDir for .jpg/.jpeg/.png files, applies the pipeline's
+// CropFunc to each, and writes the results into dstDir, mirroring the
+// source directory's relative structure. It returns an error without
+// processing anything if srcDir cannot be walked (e.g. it doesn't exist).
+func (p *Pipeline) RunDir(srcDir, dstDir string) ([]PipelineResult, error) {
+	relByPath := map[string]string{}
+	var paths []string
+
+	walkErr := filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !isImageExt(path) {
+			return nil
+		}
+		rel, relErr := filepath.Rel(srcDir, path)
+		if relErr != nil {
+			rel = filepath.Base(path)
+		}
+		relByPath[path] = rel
+		paths = append(paths, path)
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("crop: walk %s: %w", srcDir, walkErr)
+	}
+
+	sources := make(chan Source)
+	go func() {
+		defer close(sources)
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				sources <- Source{Name: path, Reader: errReader{err}}
+				continue
+			}
+			sources <- Source{Name: path, Reader: bytes.NewReader(data)}
+		}
+	}()
+
+	outputFor := func(name string) (io.WriteCloser, error) {
+		rel := relByPath[name]
+		switch p.opts.Format {
+		case ForcePNG:
+			rel = replaceExt(rel, ".png")
+		case ForceJPEG:
+			rel = replaceExt(rel, ".jpg")
+		}
+
+		dst := filepath.Join(dstDir, rel)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return nil, err
+		}
+		return os.Create(dst)
+	}
+
+	return p.runWithTotal(sources, outputFor, len(paths)), nil
+}
+
+// Run processes sources through the pipeline's CropFunc, opening each
+// destination via outputFor, and returns one PipelineResult per Source it
+// handed to a worker. In fail-fast mode, sources still arriving after the
+// first error are drained and get no PipelineResult. Use RunDir instead when walking a directory, since it also gives
+// PipelineOptions.Progress a known total.
+func (p *Pipeline) Run(sources <-chan Source, outputFor func(name string) (io.WriteCloser, error)) []PipelineResult {
+	return p.runWithTotal(sources, outputFor, 0)
+}
+
+// runWithTotal is the shared worker-pool implementation behind Run and
+// RunDir.
+func (p *Pipeline) runWithTotal(sources <-chan Source, outputFor func(string) (io.WriteCloser, error), total int) []PipelineResult {
+	jobs := make(chan Source)
+	results := make(chan PipelineResult)
+	stop := make(chan struct{})
+	var stopOnce sync.Once
+
+	var wg sync.WaitGroup
+	wg.Add(p.opts.Workers)
+	for i := 0; i < p.opts.Workers; i++ {
+		go func() {
+			defer wg.Done()
+			for src := range jobs {
+				err := p.processOne(src, outputFor)
+				results <- PipelineResult{Name: src.Name, Err: err}
+				if err != nil && !p.opts.ContinueOnError {
+					stopOnce.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for src := range sources {
+			select {
+			case jobs <- src:
+			case <-stop:
+				// Keep draining sources instead of just returning, so
+				// whatever is producing them (RunDir's file reader, or
+				// the caller's own producer for Run) doesn't block
+				// forever sending into a channel nobody reads anymore.
+				for range sources {
+				}
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var out []PipelineResult
+	done := 0
+	for res := range results {
+		done++
+		out = append(out, res)
+		if p.opts.Progress != nil {
+			p.opts.Progress(res.Name, done, total)
+		}
+	}
+	return out
+}
+
+// processOne decodes src, applies the pipeline's CropFunc, and encodes the
+// result to the destination outputFor opens for it.
+func (p *Pipeline) processOne(src Source, outputFor func(string) (io.WriteCloser, error)) error {
+	img, err := DecodeReader(src.Reader)
+	if err != nil {
+		return fmt.Errorf("crop: decode %s: %w", src.Name, err)
+	}
+
+	cropped := img
+	if p.crop != nil {
+		cropped = p.crop(img)
+	}
+
+	w, err := outputFor(src.Name)
+	if err != nil {
+		return fmt.Errorf("crop: open output for %s: %w", src.Name, err)
+	}
+	defer w.Close()
+
+	format := p.opts.Format
+	if format == AutoFormat {
+		if isPNGExt(src.Name) {
+			format = ForcePNG
+		} else {
+			format = ForceJPEG
+		}
+	}
+
+	if format == ForcePNG {
+		return SavePNG(cropped, w)
+	}
+	return SaveJPEG(cropped, w, p.opts.JPEGQuality)
+}
+
+// errReader is an io.Reader that always fails with err, used to thread a
+// file-open error from RunDir's producer goroutine through to a
+// PipelineResult.
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
+
+func isImageExt(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg", ".png":
+		return true
+	default:
+		return false
+	}
+}
+
+func isPNGExt(name string) bool {
+	return strings.EqualFold(filepath.Ext(name), ".png")
+}
+
+func replaceExt(name, ext string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name)) + ext
+}
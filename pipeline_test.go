@@ -0,0 +1,140 @@
+package crop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+// nopWriteCloser adapts a *bytes.Buffer to io.WriteCloser for tests.
+type nopWriteCloser struct{ *bytes.Buffer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func encodedTestPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("encodedTestPNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestPipelineRunSuccess(t *testing.T) {
+	data := encodedTestPNG(t)
+
+	var mu sync.Mutex
+	outputs := map[string]*bytes.Buffer{}
+
+	p := NewPipeline(func(img image.Image) image.Image { return img }, PipelineOptions{Workers: 2})
+
+	sources := make(chan Source, 2)
+	sources <- Source{Name: "a.png", Reader: bytes.NewReader(data)}
+	sources <- Source{Name: "b.png", Reader: bytes.NewReader(data)}
+	close(sources)
+
+	results := p.Run(sources, func(name string) (io.WriteCloser, error) {
+		buf := &bytes.Buffer{}
+		mu.Lock()
+		outputs[name] = buf
+		mu.Unlock()
+		return nopWriteCloser{buf}, nil
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %d, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Errorf("result for %s: unexpected error %v", r.Name, r.Err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if outputs["a.png"].Len() == 0 || outputs["b.png"].Len() == 0 {
+		t.Error("expected non-empty encoded output for both sources")
+	}
+}
+
+func TestPipelineFailFastDoesNotLeakProducer(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	p := NewPipeline(nil, PipelineOptions{Workers: 1, ContinueOnError: false})
+
+	sources := make(chan Source)
+	go func() {
+		defer close(sources)
+		for i := 0; i < 50; i++ {
+			sources <- Source{Name: fmt.Sprintf("bad-%d.png", i), Reader: errReader{errors.New("boom")}}
+		}
+	}()
+
+	results := p.Run(sources, func(name string) (io.WriteCloser, error) {
+		return nopWriteCloser{&bytes.Buffer{}}, nil
+	})
+
+	if len(results) == 0 {
+		t.Fatal("expected at least one result before fail-fast stopped the pipeline")
+	}
+	for _, r := range results {
+		if r.Err == nil {
+			t.Errorf("result for %s: expected error, got nil", r.Name)
+		}
+	}
+
+	// The producer goroutine above sends 50 sources; if the feeder
+	// abandons the channel on <-stop instead of draining it, that
+	// goroutine is left parked on a channel send forever. Give it a
+	// moment to exit and confirm the goroutine count settles back down.
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before+2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > before+2 {
+		t.Errorf("goroutine count = %d, want <= %d (producer appears leaked)", got, before+2)
+	}
+}
+
+func TestPipelineRunDirMissingDir(t *testing.T) {
+	p := NewPipeline(nil, PipelineOptions{})
+	if _, err := p.RunDir(filepath.Join(t.TempDir(), "does-not-exist"), t.TempDir()); err == nil {
+		t.Fatal("expected an error for a nonexistent source directory")
+	}
+}
+
+func TestPipelineRunDirSuccess(t *testing.T) {
+	srcDir := t.TempDir()
+	dstDir := t.TempDir()
+
+	f, err := os.Create(filepath.Join(srcDir, "photo.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	p := NewPipeline(func(img image.Image) image.Image { return img }, PipelineOptions{Workers: 2})
+	results, err := p.RunDir(srcDir, dstDir)
+	if err != nil {
+		t.Fatalf("RunDir() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("results = %+v, want one successful result", results)
+	}
+
+	if _, err := os.Stat(filepath.Join(dstDir, "photo.png")); err != nil {
+		t.Errorf("expected output file to exist: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package crop
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// Kernel selects the resampling filter used by Fit and Fill.
+type Kernel int
+
+const (
+	// NearestNeighbor is the fastest kernel; it produces blocky results
+	// when upscaling.
+	NearestNeighbor Kernel = iota
+	// ApproxBiLinear is a fast approximation of bilinear interpolation,
+	// a good default for thumbnails.
+	ApproxBiLinear
+	// CatmullRom is a higher-quality, slower cubic kernel, best for
+	// downscaling photos where sharpness matters.
+	CatmullRom
+)
+
+// interpolator maps a Kernel to its golang.org/x/image/draw implementation.
+func (k Kernel) interpolator() draw.Interpolator {
+	switch k {
+	case CatmullRom:
+		return draw.CatmullRom
+	case ApproxBiLinear:
+		return draw.ApproxBiLinear
+	default:
+		return draw.NearestNeighbor
+	}
+}
+
+// resample resizes src to width x height using kernel.
+func resample(src image.Image, width, height int, kernel Kernel) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	kernel.interpolator().Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+	return dst
+}
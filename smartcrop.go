@@ -0,0 +1,352 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// smartCropAnalysisSize is the long-edge size (in pixels) that source images
+// are downscaled to before saliency is computed. Working on a small analysis
+// image keeps SmartCrop fast regardless of the source resolution.
+const smartCropAnalysisSize = 256
+
+// SmartCropOptions tunes the weights used by SmartCrop's saliency scoring.
+// The zero value is usable but DefaultSmartCropOptions is a better starting
+// point since it balances the three terms against each other.
+type SmartCropOptions struct {
+	// EdgeWeight scales the contribution of Sobel gradient-magnitude
+	// (edge/energy) at each pixel.
+	EdgeWeight float64
+	// VarianceWeight scales the contribution of local color variance (the
+	// mean of the R, G and B channel variances) over a 3x3 window.
+	VarianceWeight float64
+	// SkinWeight scales the contribution of the skin-tone prior.
+	SkinWeight float64
+	// DisableSkinTone turns the skin-tone prior off entirely, regardless
+	// of SkinWeight.
+	DisableSkinTone bool
+	// CenterBias scales a Gaussian term that favors crop windows closer
+	// to the image center. Zero disables the bias.
+	CenterBias float64
+}
+
+// DefaultSmartCropOptions returns the weighting SmartCrop uses when called
+// without explicit options.
+func DefaultSmartCropOptions() SmartCropOptions {
+	return SmartCropOptions{
+		EdgeWeight:     1.0,
+		VarianceWeight: 0.5,
+		SkinWeight:     0.3,
+		CenterBias:     0.2,
+	}
+}
+
+// SmartCrop selects a width x height crop window automatically based on
+// image content rather than a fixed anchor. It builds an edge/energy
+// saliency map (Sobel gradient magnitude, local color variance, and a mild
+// skin-tone prior), then slides the target-sized window over it looking for
+// the position that maximizes total saliency, with a small bias toward the
+// image center.
+func SmartCrop(src image.Image, width, height int) image.Image {
+	return SmartCropWithOptions(src, width, height, DefaultSmartCropOptions())
+}
+
+// SmartCropWithOptions is SmartCrop with caller-tunable saliency weights.
+func SmartCropWithOptions(src image.Image, width, height int, opts SmartCropOptions) image.Image {
+	x, y := smartCropOrigin(src, width, height, opts)
+	return Rectangle(src, image.Rect(x, y, x+width, y+height))
+}
+
+// smartCropOrigin computes the top-left corner (in src's coordinate space)
+// of the highest-saliency width x height window.
+func smartCropOrigin(src image.Image, width, height int, opts SmartCropOptions) (int, int) {
+	bounds := src.Bounds()
+	srcW := bounds.Dx()
+	srcH := bounds.Dy()
+
+	if width > srcW {
+		width = srcW
+	}
+	if height > srcH {
+		height = srcH
+	}
+	if width <= 0 || height <= 0 {
+		return bounds.Min.X, bounds.Min.Y
+	}
+
+	longEdge := srcW
+	if srcH > longEdge {
+		longEdge = srcH
+	}
+	scale := 1.0
+	if longEdge > smartCropAnalysisSize {
+		scale = float64(smartCropAnalysisSize) / float64(longEdge)
+	}
+
+	small := downscaleNearest(src, scale)
+	saliency := saliencyMap(small, opts)
+
+	winW := int(float64(width) * scale)
+	winH := int(float64(height) * scale)
+	if winW < 1 {
+		winW = 1
+	}
+	if winH < 1 {
+		winH = 1
+	}
+	if winW > small.Bounds().Dx() {
+		winW = small.Bounds().Dx()
+	}
+	if winH > small.Bounds().Dy() {
+		winH = small.Bounds().Dy()
+	}
+
+	bestX, bestY := bestWindow(saliency, small.Bounds().Dx(), small.Bounds().Dy(), winW, winH, opts.CenterBias)
+
+	x := bounds.Min.X + int(float64(bestX)/scale)
+	y := bounds.Min.Y + int(float64(bestY)/scale)
+
+	if x+width > bounds.Max.X {
+		x = bounds.Max.X - width
+	}
+	if y+height > bounds.Max.Y {
+		y = bounds.Max.Y - height
+	}
+	if x < bounds.Min.X {
+		x = bounds.Min.X
+	}
+	if y < bounds.Min.Y {
+		y = bounds.Min.Y
+	}
+
+	return x, y
+}
+
+// downscaleNearest produces a nearest-neighbor downscaled copy of src at the
+// given scale (0 < scale <= 1). It is deliberately simple: it only feeds the
+// saliency analysis pass, not user-facing output.
+func downscaleNearest(src image.Image, scale float64) *image.RGBA {
+	bounds := src.Bounds()
+	if scale >= 1 {
+		dst := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				dst.Set(x, y, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+			}
+		}
+		return dst
+	}
+
+	dstW := int(float64(bounds.Dx()) * scale)
+	dstH := int(float64(bounds.Dy()) * scale)
+	if dstW < 1 {
+		dstW = 1
+	}
+	if dstH < 1 {
+		dstH = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		sy := bounds.Min.Y + int(float64(y)/scale)
+		for x := 0; x < dstW; x++ {
+			sx := bounds.Min.X + int(float64(x)/scale)
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+	return dst
+}
+
+// saliencyMap returns a w*h grid (row-major) of per-pixel importance scores
+// for img, combining edge energy, local color variance, and an optional
+// skin-tone prior according to opts.
+func saliencyMap(img *image.RGBA, opts SmartCropOptions) []float64 {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	lum := make([]float64, w*h)
+	var channels [3][]float64
+	for i := range channels {
+		channels[i] = make([]float64, w*h)
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y)
+			lum[y*w+x] = luminance(c)
+			channels[0][y*w+x] = float64(c.R)
+			channels[1][y*w+x] = float64(c.G)
+			channels[2][y*w+x] = float64(c.B)
+		}
+	}
+
+	saliency := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			edge := sobelMagnitude(lum, w, h, x, y)
+			variance := (localVariance(channels[0], w, h, x, y) +
+				localVariance(channels[1], w, h, x, y) +
+				localVariance(channels[2], w, h, x, y)) / 3
+
+			score := opts.EdgeWeight*edge + opts.VarianceWeight*variance
+			if !opts.DisableSkinTone {
+				c := img.RGBAAt(bounds.Min.X+x, bounds.Min.Y+y)
+				if isSkinTone(c) {
+					score += opts.SkinWeight * 255
+				}
+			}
+			saliency[y*w+x] = score
+		}
+	}
+	return saliency
+}
+
+// luminance returns the ITU-R BT.601 luma of c in the 0-255 range.
+func luminance(c color.RGBA) float64 {
+	return 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
+}
+
+// sobelMagnitude returns the Sobel gradient magnitude of the luminance grid
+// at (x, y), clamping reads at the image edges.
+func sobelMagnitude(lum []float64, w, h, x, y int) float64 {
+	at := func(px, py int) float64 {
+		if px < 0 {
+			px = 0
+		}
+		if px >= w {
+			px = w - 1
+		}
+		if py < 0 {
+			py = 0
+		}
+		if py >= h {
+			py = h - 1
+		}
+		return lum[py*w+px]
+	}
+
+	gx := -at(x-1, y-1) - 2*at(x-1, y) - at(x-1, y+1) +
+		at(x+1, y-1) + 2*at(x+1, y) + at(x+1, y+1)
+	gy := -at(x-1, y-1) - 2*at(x, y-1) - at(x+1, y-1) +
+		at(x-1, y+1) + 2*at(x, y+1) + at(x+1, y+1)
+
+	return math.Hypot(gx, gy)
+}
+
+// localVariance returns the variance of a single-channel grid over the 3x3
+// window centered at (x, y), clamping reads at the image edges.
+func localVariance(lum []float64, w, h, x, y int) float64 {
+	at := func(px, py int) float64 {
+		if px < 0 {
+			px = 0
+		}
+		if px >= w {
+			px = w - 1
+		}
+		if py < 0 {
+			py = 0
+		}
+		if py >= h {
+			py = h - 1
+		}
+		return lum[py*w+px]
+	}
+
+	var sum, sumSq float64
+	const n = 9
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			v := at(x+dx, y+dy)
+			sum += v
+			sumSq += v * v
+		}
+	}
+	mean := sum / n
+	return sumSq/n - mean*mean
+}
+
+// isSkinTone reports whether c falls inside the mild HSV skin-tone prior:
+// hue in [0, 50] degrees, saturation in [0.23, 0.68], and value > 0.35.
+func isSkinTone(c color.RGBA) bool {
+	h, s, v := rgbToHSV(c)
+	return h >= 0 && h <= 50 && s >= 0.23 && s <= 0.68 && v > 0.35
+}
+
+// rgbToHSV converts c to hue in degrees [0, 360), saturation [0, 1], and
+// value [0, 1].
+func rgbToHSV(c color.RGBA) (h, s, v float64) {
+	r := float64(c.R) / 255
+	g := float64(c.G) / 255
+	b := float64(c.B) / 255
+
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+
+	v = max
+	if max > 0 {
+		s = delta / max
+	}
+	if delta == 0 {
+		return 0, s, v
+	}
+
+	switch max {
+	case r:
+		h = 60 * math.Mod((g-b)/delta, 6)
+	case g:
+		h = 60 * ((b-r)/delta + 2)
+	case b:
+		h = 60 * ((r-g)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+	return h, s, v
+}
+
+// bestWindow slides a winW x winH window over the w x h saliency grid and
+// returns the top-left corner of the highest-scoring position. An integral
+// image keeps the per-position sum to O(1), and a Gaussian center-bias term
+// nudges ties toward the middle of the frame.
+func bestWindow(saliency []float64, w, h, winW, winH int, centerBias float64) (int, int) {
+	integral := make([]float64, (w+1)*(h+1))
+	stride := w + 1
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			integral[(y+1)*stride+(x+1)] = saliency[y*w+x] +
+				integral[y*stride+(x+1)] +
+				integral[(y+1)*stride+x] -
+				integral[y*stride+x]
+		}
+	}
+	windowSum := func(x0, y0 int) float64 {
+		x1, y1 := x0+winW, y0+winH
+		return integral[y1*stride+x1] - integral[y0*stride+x1] -
+			integral[y1*stride+x0] + integral[y0*stride+x0]
+	}
+
+	cx := float64(w) / 2
+	cy := float64(h) / 2
+	sigma := math.Max(float64(w), float64(h)) / 2
+
+	bestScore := math.Inf(-1)
+	bestX, bestY := 0, 0
+	for y := 0; y <= h-winH; y++ {
+		for x := 0; x <= w-winW; x++ {
+			score := windowSum(x, y)
+			if centerBias != 0 {
+				wcx := float64(x) + float64(winW)/2
+				wcy := float64(y) + float64(winH)/2
+				d := math.Hypot(wcx-cx, wcy-cy)
+				bias := math.Exp(-(d * d) / (2 * sigma * sigma))
+				score += centerBias * bias * float64(winW*winH)
+			}
+			if score > bestScore {
+				bestScore = score
+				bestX, bestY = x, y
+			}
+		}
+	}
+	return bestX, bestY
+}
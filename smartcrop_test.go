@@ -0,0 +1,98 @@
+package crop
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func fillUniform(img *image.RGBA, c color.Color) {
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func TestSmartCropOriginFindsSaliencyRegion(t *testing.T) {
+	const size = 64
+	src := image.NewRGBA(image.Rect(0, 0, size, size))
+	fillUniform(src, color.RGBA{R: 128, G: 128, B: 128, A: 255})
+
+	// A high-contrast checkerboard patch is the only source of edge/
+	// variance saliency in an otherwise flat image.
+	patch := image.Rect(40, 40, 56, 56)
+	for y := patch.Min.Y; y < patch.Max.Y; y++ {
+		for x := patch.Min.X; x < patch.Max.X; x++ {
+			if (x+y)%2 == 0 {
+				src.Set(x, y, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+			} else {
+				src.Set(x, y, color.RGBA{A: 255})
+			}
+		}
+	}
+
+	opts := DefaultSmartCropOptions()
+	opts.DisableSkinTone = true
+	opts.CenterBias = 0 // isolate the saliency term from the center-bias term
+
+	x, y := smartCropOrigin(src, 20, 20, opts)
+	window := image.Rect(x, y, x+20, y+20)
+
+	if window.Intersect(patch).Empty() {
+		t.Errorf("smartCropOrigin window %v does not overlap the high-saliency patch %v", window, patch)
+	}
+}
+
+func TestSmartCropDisableSkinToneChangesResult(t *testing.T) {
+	const size = 64
+	src := image.NewRGBA(image.Rect(0, 0, size, size))
+	fillUniform(src, color.RGBA{B: 255, A: 255}) // flat, non-skin background
+
+	// A skin-toned patch away from the top-left corner; with every other
+	// weight at zero it's the only source of saliency.
+	patch := image.Rect(40, 40, 60, 60)
+	skin := color.RGBA{R: 241, G: 194, B: 125, A: 255}
+	for y := patch.Min.Y; y < patch.Max.Y; y++ {
+		for x := patch.Min.X; x < patch.Max.X; x++ {
+			src.Set(x, y, skin)
+		}
+	}
+
+	base := SmartCropOptions{SkinWeight: 1, CenterBias: 0}
+
+	enabled := base
+	enabled.DisableSkinTone = false
+	xEnabled, yEnabled := smartCropOrigin(src, 20, 20, enabled)
+	windowEnabled := image.Rect(xEnabled, yEnabled, xEnabled+20, yEnabled+20)
+
+	disabled := base
+	disabled.DisableSkinTone = true
+	xDisabled, yDisabled := smartCropOrigin(src, 20, 20, disabled)
+	windowDisabled := image.Rect(xDisabled, yDisabled, xDisabled+20, yDisabled+20)
+
+	if windowEnabled.Intersect(patch).Empty() {
+		t.Errorf("with skin-tone prior enabled, window %v should overlap the skin-toned patch %v", windowEnabled, patch)
+	}
+	if !windowDisabled.Intersect(patch).Empty() {
+		t.Errorf("with skin-tone prior disabled, window %v should not be drawn toward the skin-toned patch %v", windowDisabled, patch)
+	}
+	if windowEnabled == windowDisabled {
+		t.Error("DisableSkinTone should change the chosen window, but both runs picked the same one")
+	}
+}
+
+func TestSmartCropClampsToSourceSize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 10, 8))
+	out := SmartCrop(src, 100, 50)
+
+	b := out.Bounds()
+	if b.Dx() != 10 || b.Dy() != 8 {
+		t.Errorf("SmartCrop() with oversized target = %dx%d, want 10x8 (clamped to source)", b.Dx(), b.Dy())
+	}
+}
+
+func TestSmartCropOriginRejectsNonPositiveSize(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	x, y := smartCropOrigin(src, 0, 0, DefaultSmartCropOptions())
+	if x != src.Bounds().Min.X || y != src.Bounds().Min.Y {
+		t.Errorf("smartCropOrigin() with zero size = (%d, %d), want source origin (%d, %d)", x, y, src.Bounds().Min.X, src.Bounds().Min.Y)
+	}
+}